@@ -0,0 +1,267 @@
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/Tnze/go-mc/data/block"
+)
+
+// BlockGetter looks up the block present at a world position. Implementations
+// are typically backed by a client's loaded chunks.
+type BlockGetter func(x, y, z int) block.ID
+
+// Pos is a block position in world coordinates.
+type Pos struct {
+	X, Y, Z int
+}
+
+const (
+	entityWidth  = 0.6
+	entityHeight = 1.8
+
+	defaultMaxFall = 3
+)
+
+// Finder searches for a walkable path between two block positions using A*.
+//
+// The zero value is not usable; create one with NewFinder.
+type Finder struct {
+	Blocks BlockGetter
+
+	// Registry resolves the block IDs Blocks returns into block.Block data,
+	// e.g. the Registry for the connection's own protocol version from
+	// block.ForProtocol.
+	Registry block.Registry
+
+	// MaxFall is the greatest number of blocks a single step of the path may
+	// drop. It defaults to 3 when left zero.
+	MaxFall int
+
+	// AllowDigging lets the path cross diggable blocks that need no tool.
+	// Only a level step is charged for digging: it costs 1 plus the Hardness
+	// of the block at the entity's feet. The block at head height, and blocks
+	// cleared for a step up, a jump or a fall, add no cost.
+	AllowDigging bool
+}
+
+// NewFinder creates a Finder that queries world state through get, resolving
+// block IDs against reg.
+func NewFinder(get BlockGetter, reg block.Registry) *Finder {
+	return &Finder{Blocks: get, Registry: reg, MaxFall: defaultMaxFall}
+}
+
+// FindPath searches for a path from start to goal, both given in block
+// coordinates. The returned waypoints include start and goal, in order. ok is
+// false if no path was found before the search expanded 100000 positions.
+func (f *Finder) FindPath(start, goal Pos) (waypoints []Pos, ok bool) {
+	// The world behind Blocks may be unbounded, so the search gives up after
+	// expanding maxExpanded positions.
+	const maxExpanded = 100000
+
+	maxFall := f.MaxFall
+	if maxFall <= 0 {
+		maxFall = defaultMaxFall
+	}
+
+	open := &nodeHeap{}
+	heap.Init(open)
+	heap.Push(open, &node{pos: start, f: octile(start, goal)})
+
+	gScore := map[Pos]float64{start: 0}
+	cameFrom := map[Pos]Pos{}
+	closed := map[Pos]struct{}{}
+
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(*node)
+		if _, done := closed[cur.pos]; done {
+			continue
+		}
+		if cur.pos == goal {
+			return reconstruct(cameFrom, goal), true
+		}
+		closed[cur.pos] = struct{}{}
+		if len(closed) > maxExpanded {
+			break
+		}
+
+		for _, s := range f.successors(cur.pos, maxFall) {
+			if _, done := closed[s.pos]; done {
+				continue
+			}
+			g := gScore[cur.pos] + s.cost
+			if old, ok := gScore[s.pos]; ok && g >= old {
+				continue
+			}
+			gScore[s.pos] = g
+			cameFrom[s.pos] = cur.pos
+			heap.Push(open, &node{pos: s.pos, f: g + octile(s.pos, goal)})
+		}
+	}
+	return nil, false
+}
+
+func reconstruct(cameFrom map[Pos]Pos, goal Pos) []Pos {
+	path := []Pos{goal}
+	for {
+		prev, ok := cameFrom[path[len(path)-1]]
+		if !ok {
+			break
+		}
+		path = append(path, prev)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// octile is the 3D octile-distance heuristic: octile distance across the
+// horizontal plane plus the vertical difference.
+func octile(a, b Pos) float64 {
+	dx := math.Abs(float64(a.X - b.X))
+	dz := math.Abs(float64(a.Z - b.Z))
+	dy := math.Abs(float64(a.Y - b.Y))
+	const d, d2 = 1, math.Sqrt2
+	horiz := d*(dx+dz) + (d2-2*d)*math.Min(dx, dz)
+	return horiz + dy
+}
+
+type successor struct {
+	pos  Pos
+	cost float64
+}
+
+var cardinal = [4]Pos{{X: 1}, {X: -1}, {Z: 1}, {Z: -1}}
+
+// successors enumerates the positions reachable from pos in a single step:
+// a level step in each cardinal direction, a step up of one block, a walk
+// off a ledge that falls up to maxFall blocks, and a jump across a
+// one-block gap.
+func (f *Finder) successors(pos Pos, maxFall int) []successor {
+	var out []successor
+	for _, d := range cardinal {
+		level := Pos{pos.X + d.X, pos.Y, pos.Z + d.Z}
+		if cost, ok := f.stepCost(level); ok {
+			out = append(out, successor{level, cost})
+			continue
+		}
+
+		up := Pos{pos.X + d.X, pos.Y + 1, pos.Z + d.Z}
+		if f.canOccupy(up) && f.canOccupy(Pos{pos.X, pos.Y + 1, pos.Z}) && f.isSolid(Pos{up.X, up.Y - 1, up.Z}) {
+			out = append(out, successor{up, 1})
+		}
+
+		if cost, ok := f.jumpCost(pos, d); ok {
+			out = append(out, successor{Pos{pos.X + 2*d.X, pos.Y, pos.Z + 2*d.Z}, cost})
+		}
+
+		for dy := 1; dy <= maxFall; dy++ {
+			down := Pos{pos.X + d.X, pos.Y - dy, pos.Z + d.Z}
+			if cost, ok := f.fallCost(pos, down); ok {
+				out = append(out, successor{down, cost})
+			}
+		}
+	}
+	return out
+}
+
+// stepCost returns the cost of moving onto pos at the current level, and
+// whether that move is possible at all.
+func (f *Finder) stepCost(pos Pos) (float64, bool) {
+	if !f.canOccupy(pos) || !f.isSolid(Pos{pos.X, pos.Y - 1, pos.Z}) {
+		return 0, false
+	}
+	return f.diggingCost(pos), true
+}
+
+func (f *Finder) jumpCost(pos, d Pos) (float64, bool) {
+	gap := Pos{pos.X + d.X, pos.Y, pos.Z + d.Z}
+	land := Pos{pos.X + 2*d.X, pos.Y, pos.Z + 2*d.Z}
+	if f.isSolid(Pos{gap.X, gap.Y - 1, gap.Z}) {
+		return 0, false // not actually a gap
+	}
+	if !f.canOccupy(gap) || !f.canOccupy(land) || !f.isSolid(Pos{land.X, land.Y - 1, land.Z}) {
+		return 0, false
+	}
+	return 1.2, true
+}
+
+// fallCost reports the cost of stepping off the ledge at from into the
+// column at (to.X, to.Z) and falling until landing at to. Unlike stepCost,
+// it doesn't require solid footing directly below from: the entity is
+// allowed to walk off the edge and drop, as long as every block it passes
+// through on the way down - including the level it steps off at - is
+// clear, and there's solid ground to land on.
+func (f *Finder) fallCost(from, to Pos) (float64, bool) {
+	for y := from.Y; y >= to.Y; y-- {
+		if !f.canOccupy(Pos{to.X, y, to.Z}) {
+			return 0, false
+		}
+	}
+	if !f.isSolid(Pos{to.X, to.Y - 1, to.Z}) {
+		return 0, false
+	}
+	return 1 + float64(from.Y-to.Y)*0.1, true
+}
+
+// canOccupy reports whether the 0.6x1.8 bounding box of the entity fits with
+// its feet at pos, i.e. pos and the block above it are clear.
+func (f *Finder) canOccupy(pos Pos) bool {
+	return f.isClear(pos) && f.isClear(Pos{pos.X, pos.Y + 1, pos.Z})
+}
+
+// isClear reports whether pos can be occupied by the entity: either it is
+// transparent (air, open door, ...), or it is diggable and digging is
+// allowed and doesn't require a tool the bot is assumed not to carry.
+func (f *Finder) isClear(pos Pos) bool {
+	b := f.block(pos)
+	if b == nil {
+		return false
+	}
+	if b.Transparent {
+		return true
+	}
+	return f.AllowDigging && b.Diggable && len(b.NeedsTools) == 0
+}
+
+// isSolid reports whether pos is solid ground the entity can stand on.
+func (f *Finder) isSolid(pos Pos) bool {
+	b := f.block(pos)
+	return b != nil && !b.Transparent
+}
+
+// diggingCost is the A* cost of a level step onto pos: 1, plus the Hardness
+// of the block at pos when it is solid and must be dug out first.
+func (f *Finder) diggingCost(pos Pos) float64 {
+	b := f.block(pos)
+	if b == nil || b.Transparent {
+		return 1
+	}
+	return 1 + b.Hardness
+}
+
+func (f *Finder) block(pos Pos) *block.Block {
+	id := f.Blocks(pos.X, pos.Y, pos.Z)
+	return f.Registry.Lookup(id)
+}
+
+// node is an entry of the A* open list, ordered by its f-score.
+type node struct {
+	pos Pos
+	f   float64
+}
+
+type nodeHeap []*node
+
+func (h nodeHeap) Len() int            { return len(h) }
+func (h nodeHeap) Less(i, j int) bool  { return h[i].f < h[j].f }
+func (h nodeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x interface{}) { *h = append(*h, x.(*node)) }
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
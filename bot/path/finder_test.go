@@ -0,0 +1,113 @@
+package path
+
+import (
+	"testing"
+
+	"github.com/Tnze/go-mc/data/block"
+)
+
+const (
+	idAir   block.ID = 0
+	idStone block.ID = 1
+)
+
+func testRegistry(t *testing.T) block.Registry {
+	t.Helper()
+	reg := block.NewRegistry()
+	if err := reg.Register(&block.Block{ID: idAir, Name: "air", Transparent: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(&block.Block{ID: idStone, Name: "stone", MinStateID: 1, MaxStateID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func TestFindPathFlatGround(t *testing.T) {
+	f := NewFinder(func(x, y, z int) block.ID {
+		if y < 0 {
+			return idStone
+		}
+		return idAir
+	}, testRegistry(t))
+
+	path, ok := f.FindPath(Pos{0, 0, 0}, Pos{5, 0, 0})
+	if !ok {
+		t.Fatalf("expected a path across flat ground")
+	}
+	if got := path[0]; got != (Pos{0, 0, 0}) {
+		t.Errorf("path should start at the start position, got %v", got)
+	}
+	if got := path[len(path)-1]; got != (Pos{5, 0, 0}) {
+		t.Errorf("path should end at the goal, got %v", got)
+	}
+}
+
+func TestFindPathNoPathAcrossWideChasm(t *testing.T) {
+	// x in [1,2] has no floor at any height: a chasm two blocks wide, too
+	// wide for a one-block jump to clear.
+	world := func(x, y, z int) block.ID {
+		if x == 1 || x == 2 {
+			return idAir
+		}
+		if y < 0 {
+			return idStone
+		}
+		return idAir
+	}
+
+	f := NewFinder(world, testRegistry(t))
+	if _, ok := f.FindPath(Pos{0, 0, 0}, Pos{3, 0, 0}); ok {
+		t.Fatalf("expected no path across a chasm wider than a single jump")
+	}
+}
+
+func TestFindPathJumpsOneBlockGap(t *testing.T) {
+	// x == 1 has no floor, but is only one block wide: the pathfinder
+	// should clear it with a jump rather than fail or fall.
+	world := func(x, y, z int) block.ID {
+		if x == 1 && y == -1 {
+			return idAir
+		}
+		if y < 0 {
+			return idStone
+		}
+		return idAir
+	}
+
+	f := NewFinder(world, testRegistry(t))
+	path, ok := f.FindPath(Pos{0, 0, 0}, Pos{2, 0, 0})
+	if !ok {
+		t.Fatalf("expected the pathfinder to jump across a one-block gap")
+	}
+	if len(path) != 2 || path[1] != (Pos{2, 0, 0}) {
+		t.Errorf("expected a single jump straight to %v, got %v", Pos{2, 0, 0}, path)
+	}
+}
+
+func TestFindPathFallLimitedByMaxFall(t *testing.T) {
+	// Solid ground at x=0. x=1 is a 5-block-deep pit, open at the top and
+	// floored at the bottom, so reaching it requires walking off the ledge
+	// at x=0 and falling - not starting the search already mid-air.
+	world := func(x, y, z int) block.ID {
+		if x == 1 && y < 0 && y >= -5 {
+			return idAir
+		}
+		if y < 0 {
+			return idStone
+		}
+		return idAir
+	}
+
+	f := NewFinder(world, testRegistry(t))
+
+	f.MaxFall = 3
+	if _, ok := f.FindPath(Pos{0, 0, 0}, Pos{1, -5, 0}); ok {
+		t.Fatalf("expected a 5-block fall to be unreachable with MaxFall=3")
+	}
+
+	f.MaxFall = 5
+	if _, ok := f.FindPath(Pos{0, 0, 0}, Pos{1, -5, 0}); !ok {
+		t.Fatalf("expected walking off the ledge and falling 5 blocks to be reachable with MaxFall=5")
+	}
+}
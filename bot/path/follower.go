@@ -0,0 +1,59 @@
+package path
+
+import "math"
+
+// arriveDist is how close, in blocks, the entity must get to a waypoint
+// before the Follower advances to the next one.
+const arriveDist = 0.3
+
+// Follower walks an entity along the waypoints produced by Finder.FindPath,
+// turning them into a stream of Inputs.
+type Follower struct {
+	waypoints []Pos
+	next      int
+}
+
+// NewFollower creates a Follower that walks the given waypoints in order.
+func NewFollower(waypoints []Pos) *Follower {
+	return &Follower{waypoints: waypoints}
+}
+
+// Done reports whether every waypoint has been reached.
+func (fw *Follower) Done() bool {
+	return fw.next >= len(fw.waypoints)
+}
+
+// Next computes the Inputs needed to keep moving along the path, given the
+// entity's current position in world coordinates. It advances past any
+// waypoint already reached. Once Done, Next returns the zero Inputs.
+func (fw *Follower) Next(x, y, z float64) Inputs {
+	for !fw.Done() {
+		wp := fw.waypoints[fw.next]
+		dx := float64(wp.X) + 0.5 - x
+		dz := float64(wp.Z) + 0.5 - z
+		if dx*dx+dz*dz < arriveDist*arriveDist && math.Abs(float64(wp.Y)-y) < 1 {
+			fw.next++
+			continue
+		}
+
+		in := Inputs{
+			Yaw:       math.Atan2(-dx, dz) * 180 / math.Pi,
+			ThrottleZ: 1,
+		}
+		if fw.next > 0 {
+			prev := fw.waypoints[fw.next-1]
+			climb := wp.Y > prev.Y
+			gap := abs(wp.X-prev.X)+abs(wp.Z-prev.Z) > 1
+			in.Jump = climb || gap
+		}
+		return in
+	}
+	return Inputs{}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,107 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	pk "github.com/Tnze/go-mc/net/packet"
+)
+
+func TestWriteReplayRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Meta{ProtocolVersion: 754, CompressionThreshold: 256})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+
+	want := []struct {
+		dir Direction
+		pkt pk.Packet
+	}{
+		{Clientbound, pk.Packet{ID: 0x24, Data: []byte{1, 2, 3}}},
+		{Serverbound, pk.Packet{ID: 0x10, Data: []byte{}}},
+		{Clientbound, pk.Packet{ID: 0x00, Data: bytes.Repeat([]byte{0xAB}, 300)}},
+	}
+	for _, rec := range want {
+		if err := w.Write(rec.dir, rec.pkt); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	rp, err := NewReplayer(&buf)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+	if rp.Meta != (Meta{ProtocolVersion: 754, CompressionThreshold: 256}) {
+		t.Errorf("Meta = %+v, want %+v", rp.Meta, Meta{ProtocolVersion: 754, CompressionThreshold: 256})
+	}
+
+	for i, want := range want {
+		got, err := rp.Next()
+		if err != nil {
+			t.Fatalf("Next() #%d: %v", i, err)
+		}
+		if got.Direction != want.dir {
+			t.Errorf("record #%d Direction = %v, want %v", i, got.Direction, want.dir)
+		}
+		if got.Packet.ID != want.pkt.ID {
+			t.Errorf("record #%d Packet.ID = %d, want %d", i, got.Packet.ID, want.pkt.ID)
+		}
+		if !bytes.Equal(got.Packet.Data, want.pkt.Data) {
+			t.Errorf("record #%d Packet.Data = %v, want %v", i, got.Packet.Data, want.pkt.Data)
+		}
+	}
+
+	if _, err := rp.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Next() after the last record = %v, want io.EOF", err)
+	}
+}
+
+func TestNewReplayerRejectsBadMagic(t *testing.T) {
+	_, err := NewReplayer(bytes.NewReader([]byte("not a capture file at all")))
+	if err == nil {
+		t.Fatalf("expected an error for a file with the wrong magic")
+	}
+}
+
+type recordingSink struct {
+	packets []pk.Packet
+}
+
+func (s *recordingSink) HandlePacket(p pk.Packet) error {
+	s.packets = append(s.packets, p)
+	return nil
+}
+
+func TestReplayToSkipsServerbound(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Meta{})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.Write(Serverbound, pk.Packet{ID: 1, Data: []byte("client hello")}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Write(Clientbound, pk.Packet{ID: 2, Data: []byte("server reply")}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	rp, err := NewReplayer(&buf)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+
+	sink := &recordingSink{}
+	if err := rp.ReplayTo(sink, 0); err != nil {
+		t.Fatalf("ReplayTo: %v", err)
+	}
+
+	if len(sink.packets) != 1 {
+		t.Fatalf("ReplayTo delivered %d packets, want 1 (serverbound should be skipped)", len(sink.packets))
+	}
+	if sink.packets[0].ID != 2 {
+		t.Errorf("delivered packet ID = %d, want 2", sink.packets[0].ID)
+	}
+}
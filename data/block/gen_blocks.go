@@ -1,4 +1,5 @@
-// gen_blocks.go generates block information.
+// gen_blocks.go generates block information for every supported Minecraft
+// version, one sub-package per version.
 
 //+build ignore
 
@@ -10,17 +11,44 @@ import (
 	"go/ast"
 	"go/format"
 	"go/token"
+	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 
 	"github.com/iancoleman/strcase"
 )
 
-const (
-	infoURL = "https://raw.githubusercontent.com/PrismarineJS/minecraft-data/master/data/pc/1.16.2/blocks.json"
-)
+// version describes one Minecraft release this module generates block data
+// for.
+type version struct {
+	// DataVersion is the minecraft-data pc version directory, e.g. "1.16.2".
+	DataVersion string
+	// Protocol is the protocol version number sent during the login
+	// handshake by this release.
+	Protocol int32
+	// Package is the name of the generated sub-package, e.g. "v1_16_2".
+	Package string
+	// Default marks the version whose blocks also populate DefaultRegistry,
+	// for callers that still use the package-level ByID/StateID tables.
+	Default bool
+}
+
+// versions lists every release this generator emits a sub-package for. It
+// intentionally starts at 1.16.2: minecraft-data's blocks.json for 1.12.2
+// and earlier predates the flattened block-state model (no
+// minStateId/maxStateId/states), which this generator assumes throughout -
+// see validate. Supporting those releases would need a separate
+// metadata-based decoding path, not just a new table entry.
+var versions = []version{
+	{DataVersion: "1.16.2", Protocol: 751, Package: "v1_16_2", Default: true},
+	{DataVersion: "1.16.5", Protocol: 754, Package: "v1_16_5"},
+	{DataVersion: "1.18.2", Protocol: 758, Package: "v1_18_2"},
+	{DataVersion: "1.19.4", Protocol: 762, Package: "v1_19_4"},
+	{DataVersion: "1.20.1", Protocol: 763, Package: "v1_20_1"},
+}
 
 type Block struct {
 	ID          uint32 `json:"id"`
@@ -38,23 +66,70 @@ type Block struct {
 	Transparent      bool `json:"transparent"`
 	FilterLightLevel int  `json:"filterLight"`
 	EmitLightLevel   int  `json:"emitLight"`
+
+	Properties []StateProp `json:"states"`
+}
+
+// StateProp is the minecraft-data representation of one property a block's
+// state is made of, e.g. {"name": "facing", "type": "enum", "values": [...]}.
+type StateProp struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Values []string `json:"values"`
 }
 
-func downloadInfo() ([]Block, error) {
-	resp, err := http.Get(infoURL)
+func infoURL(v version) string {
+	return fmt.Sprintf("https://raw.githubusercontent.com/PrismarineJS/minecraft-data/master/data/pc/%s/blocks.json", v.DataVersion)
+}
+
+func testdataPath(v version) string {
+	return filepath.Join("testdata", v.DataVersion+"-blocks.json")
+}
+
+// downloadInfo returns v's blocks.json, from testdata/ if it was already
+// fetched once, so regenerating doesn't need network access and stays
+// reproducible in CI.
+func downloadInfo(v version) ([]Block, error) {
+	path := testdataPath(v)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		resp, err := http.Get(infoURL(v))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		data, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, err
+		}
 	}
-	defer resp.Body.Close()
 
-	var data []Block
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	var blocks []Block
+	if err := json.Unmarshal(data, &blocks); err != nil {
 		return nil, err
 	}
-	return data, nil
+	return blocks, nil
 }
 
-func makeBlockDeclaration(blocks []Block) *ast.DeclStmt {
+func stringSliceLit(ss []string) *ast.CompositeLit {
+	lit := &ast.CompositeLit{Type: &ast.ArrayType{Elt: &ast.Ident{Name: "string"}}}
+	for _, s := range ss {
+		lit.Elts = append(lit.Elts, &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)})
+	}
+	return lit
+}
+
+// makeBlockDeclaration builds `var Stone = block.Block{...}`-style
+// declarations for every block, qualifying the block package's exported
+// types with qualifier (e.g. "block.").
+func makeBlockDeclaration(blocks []Block, qualifier string) *ast.DeclStmt {
 	out := &ast.DeclStmt{Decl: &ast.GenDecl{Tok: token.VAR}}
 
 	for _, b := range blocks {
@@ -76,9 +151,16 @@ func makeBlockDeclaration(blocks []Block) *ast.DeclStmt {
 				val = &ast.BasicLit{Kind: token.IDENT, Value: fmt.Sprint(reflect.ValueOf(b).Field(i).Bool())}
 
 			case reflect.Slice:
+				eltName := ft.Type.Elem().Name()
+				if ft.Type.Elem() == reflect.TypeOf(StateProp{}) {
+					// Properties is generated as []block.Property, not
+					// []StateProp: StateProp is only the shape
+					// minecraft-data serializes.
+					eltName = qualifier + "Property"
+				}
 				val = &ast.CompositeLit{
 					Type: &ast.ArrayType{
-						Elt: &ast.BasicLit{Kind: token.IDENT, Value: ft.Type.Elem().Name()},
+						Elt: &ast.BasicLit{Kind: token.IDENT, Value: eltName},
 					},
 				}
 				v := reflect.ValueOf(b).Field(i)
@@ -90,6 +172,17 @@ func makeBlockDeclaration(blocks []Block) *ast.DeclStmt {
 							Value: fmt.Sprint(v.Index(x)),
 						})
 					}
+				case reflect.Struct:
+					for x := 0; x < v.Len(); x++ {
+						p := v.Index(x).Interface().(StateProp)
+						val.(*ast.CompositeLit).Elts = append(val.(*ast.CompositeLit).Elts, &ast.CompositeLit{
+							Elts: []ast.Expr{
+								&ast.KeyValueExpr{Key: &ast.Ident{Name: "Name"}, Value: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(p.Name)}},
+								&ast.KeyValueExpr{Key: &ast.Ident{Name: "Type"}, Value: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(p.Type)}},
+								&ast.KeyValueExpr{Key: &ast.Ident{Name: "Values"}, Value: stringSliceLit(p.Values)},
+							},
+						})
+					}
 				}
 
 			case reflect.Map:
@@ -121,7 +214,7 @@ func makeBlockDeclaration(blocks []Block) *ast.DeclStmt {
 			Names: []*ast.Ident{{Name: strcase.ToCamel(b.Name)}},
 			Values: []ast.Expr{
 				&ast.CompositeLit{
-					Type: &ast.Ident{Name: reflect.TypeOf(b).Name()},
+					Type: &ast.Ident{Name: qualifier + "Block"},
 					Elts: fields,
 				},
 			},
@@ -131,69 +224,125 @@ func makeBlockDeclaration(blocks []Block) *ast.DeclStmt {
 	return out
 }
 
-func main() {
-	blocks, err := downloadInfo()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+// validate sanity-checks that v's blocks.json actually carries the
+// flattened block-state shape (minStateId/maxStateId/states) this generator
+// assumes, rather than trusting it blindly. A pre-flattening release
+// mistakenly added to versions would otherwise silently generate an empty
+// or wrong table; this makes that failure loud instead.
+func validate(v version, blocks []Block) error {
+	if len(blocks) == 0 {
+		return fmt.Errorf("%s: blocks.json has no blocks", v.DataVersion)
 	}
 
-	fmt.Println(`// Package block stores information about blocks in Minecraft.
-package block
-
-import (
-	"math"
-)
-
-// BitsPerBlock indicates how many bits are needed to represent all possible
-// block states. This value is used to determine the size of the global palette.
-var BitsPerBlock = int(math.Ceil(math.Log2(float64(len(StateID)))))
+	type stateRange struct {
+		name     string
+		min, max uint32
+	}
+	var ranges []stateRange
+	flattened := false
 
-// ID describes the numeric ID of a block.
-type ID uint32
+	for _, b := range blocks {
+		if b.MaxStateID < b.MinStateID {
+			return fmt.Errorf("%s: block %q has maxStateId %d < minStateId %d", v.DataVersion, b.Name, b.MaxStateID, b.MinStateID)
+		}
+		if b.MaxStateID > b.MinStateID || len(b.Properties) > 0 {
+			flattened = true
+		}
 
-// Block describes information about a type of block.
-type Block struct {
-	ID          ID
-	DisplayName string
-	Name        string
+		for _, r := range ranges {
+			if b.MinStateID <= r.max && r.min <= b.MaxStateID {
+				return fmt.Errorf("%s: block %q's state range [%d,%d] overlaps %q's [%d,%d]",
+					v.DataVersion, b.Name, b.MinStateID, b.MaxStateID, r.name, r.min, r.max)
+			}
+		}
+		ranges = append(ranges, stateRange{b.Name, b.MinStateID, b.MaxStateID})
 
-	Hardness   float64
-	Diggable   bool
-	DropIDs    []uint32
-	NeedsTools map[uint32]bool
+		if combos := propertyCombinations(b.Properties); combos != 0 && combos != int(b.MaxStateID-b.MinStateID+1) {
+			return fmt.Errorf("%s: block %q has %d property combinations but a state range of size %d",
+				v.DataVersion, b.Name, combos, b.MaxStateID-b.MinStateID+1)
+		}
+	}
 
-	MinStateID uint32
-	MaxStateID uint32
+	if !flattened {
+		return fmt.Errorf("%s: every block has a single, empty state; this minecraft-data release likely predates the flattened block-state model this generator assumes", v.DataVersion)
+	}
+	return nil
+}
 
-	Transparent      bool
-	FilterLightLevel int
-	EmitLightLevel   int
+// propertyCombinations returns how many distinct states props describes, or
+// 0 if any property lists no values.
+func propertyCombinations(props []StateProp) int {
+	n := 1
+	for _, p := range props {
+		if len(p.Values) == 0 {
+			return 0
+		}
+		n *= len(p.Values)
+	}
+	return n
 }
 
-`)
-	format.Node(os.Stdout, token.NewFileSet(), makeBlockDeclaration(blocks))
+func generate(v version) error {
+	blocks, err := downloadInfo(v)
+	if err != nil {
+		return err
+	}
+	if err := validate(v, blocks); err != nil {
+		return err
+	}
 
-	fmt.Println()
-	fmt.Println()
-	fmt.Println("// ByID is an index of minecraft blocks by their ID.")
-	fmt.Println("var ByID = map[ID]*Block{")
+	if err := os.MkdirAll(v.Package, 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(v.Package, "tables.go"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "// Package %s stores block information generated for Minecraft %s (protocol %d).\n", v.Package, v.DataVersion, v.Protocol)
+	fmt.Fprintf(f, "package %s\n\n", v.Package)
+	fmt.Fprintln(f, `import "github.com/Tnze/go-mc/data/block"`)
+	fmt.Fprintln(f)
+
+	format.Node(f, token.NewFileSet(), makeBlockDeclaration(blocks, "block."))
+
+	fmt.Fprintln(f)
+	fmt.Fprintln(f)
+	fmt.Fprintln(f, "// Blocks is this version's own Registry, registered with the block package")
+	fmt.Fprintf(f, "// under protocol version %d.\n", v.Protocol)
+	fmt.Fprintln(f, "var Blocks = block.NewRegistry()")
+	fmt.Fprintln(f)
+	fmt.Fprintln(f, "func init() {")
 	for _, b := range blocks {
-		fmt.Printf("  %d: &%s,\n", b.ID, strcase.ToCamel(b.Name))
+		name := strcase.ToCamel(b.Name)
+		fmt.Fprintf(f, "  must(Blocks.Register(&%s))\n", name)
+		if v.Default {
+			fmt.Fprintf(f, "  must(block.Register(&%s))\n", name)
+		}
 	}
-	fmt.Println("}")
+	fmt.Fprintf(f, "  block.RegisterVersion(%d, Blocks)\n", v.Protocol)
+	fmt.Fprintln(f, "}")
+	fmt.Fprintln(f)
+	fmt.Fprintln(f, "func must(err error) {")
+	fmt.Fprintln(f, "  if err != nil {")
+	fmt.Fprintln(f, "    panic(err)")
+	fmt.Fprintln(f, "  }")
+	fmt.Fprintln(f, "}")
+
+	return nil
+}
 
-	fmt.Println()
-	fmt.Println("// StateID maps all possible state IDs to a corresponding block ID.")
-	fmt.Println("var StateID = map[uint32]ID{")
-	for _, b := range blocks {
-		if b.MinStateID == b.MaxStateID {
-			fmt.Printf("  %d: %d,\n", b.MinStateID, b.ID)
-		} else {
-			for i := b.MinStateID; i <= b.MaxStateID; i++ {
-				fmt.Printf("  %d: %d,\n", i, b.ID)
-			}
+func main() {
+	failed := false
+	for _, v := range versions {
+		if err := generate(v); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating %s: %v\n", v.DataVersion, err)
+			failed = true
+			continue
 		}
 	}
-	fmt.Println("}")
-}
\ No newline at end of file
+	if failed {
+		os.Exit(1)
+	}
+}
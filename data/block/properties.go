@@ -0,0 +1,38 @@
+package block
+
+// Property describes one axis of a block's state, such as facing or
+// waterlogged. A block's state ID, offset from its MinStateID, selects one
+// value from each of its Properties.
+type Property struct {
+	Name string
+	// Type is "enum", "bool" or "int", as classified by minecraft-data.
+	Type string
+	// Values holds every value this property can take, in the order used to
+	// encode it into the block's state ID.
+	Values []string
+}
+
+// StateProperties decodes a state ID back into the property values it
+// represents, e.g. {"facing": "north", "waterlogged": "false"}. It returns
+// nil if state doesn't belong to a known block, or that block has no
+// properties (its state space is a single state).
+func StateProperties(state uint32) map[string]string {
+	id := DefaultRegistry.StateToID(state)
+	b := DefaultRegistry.Lookup(id)
+	if b == nil || len(b.Properties) == 0 {
+		return nil
+	}
+
+	// Properties are encoded as a mixed-radix number over state-MinStateID,
+	// with the last property varying fastest - matching the nesting order
+	// minecraft-data lists them in.
+	offset := state - b.MinStateID
+	out := make(map[string]string, len(b.Properties))
+	for i := len(b.Properties) - 1; i >= 0; i-- {
+		p := b.Properties[i]
+		n := uint32(len(p.Values))
+		out[p.Name] = p.Values[offset%n]
+		offset /= n
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package block
+
+import "testing"
+
+func TestStateProperties(t *testing.T) {
+	b := &Block{
+		ID:         9001,
+		Name:       "test_stateful_block",
+		MinStateID: 500,
+		MaxStateID: 507,
+		Properties: []Property{
+			{Name: "facing", Type: "enum", Values: []string{"north", "south", "east", "west"}},
+			{Name: "waterlogged", Type: "bool", Values: []string{"true", "false"}},
+		},
+	}
+	if err := Register(b); err != nil {
+		t.Fatal(err)
+	}
+
+	got := StateProperties(503)
+	want := map[string]string{"facing": "south", "waterlogged": "false"}
+	if len(got) != len(want) {
+		t.Fatalf("StateProperties(503) = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("StateProperties(503)[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestStatePropertiesUnknownState(t *testing.T) {
+	if got := StateProperties(1 << 30); got != nil {
+		t.Errorf("StateProperties of an unknown state = %v, want nil", got)
+	}
+}
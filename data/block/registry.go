@@ -0,0 +1,145 @@
+package block
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Registry looks up block information by ID or by state ID, and lets callers
+// extend it with blocks that weren't known at generation time. Code that
+// works with non-vanilla block data - a modded server, or a different
+// Minecraft version - can build its own Registry with NewRegistry instead of
+// mutating the package-level ByID and StateID tables.
+type Registry interface {
+	// Lookup returns the Block registered under id, or nil if none is.
+	Lookup(id ID) *Block
+	// StateToID returns the ID of the block that owns the given state ID.
+	StateToID(state uint32) ID
+	// All returns every Block known to the registry.
+	All() []*Block
+	// BitsPerBlock is how many bits are needed to represent every state this
+	// registry knows about. It is used to size the global palette.
+	BitsPerBlock() int
+
+	// Register adds b to the registry, extending its ByID and StateID
+	// tables with b's state range. It returns an error if a block with the
+	// same ID is already registered.
+	Register(b *Block) error
+	// RegisterStates extends the state range owned by an already-registered
+	// block, for blocks whose state space grows after being registered (e.g.
+	// a mod adding new property combinations to a vanilla block).
+	RegisterStates(id ID, minState, maxState uint32)
+}
+
+// ByID is an index of minecraft blocks by their ID. It is a view of
+// DefaultRegistry kept for callers that predate Registry; new code should
+// prefer going through DefaultRegistry or a version-specific Registry from
+// ForProtocol.
+var ByID = map[ID]*Block{}
+
+// StateID maps all possible state IDs to their owning block ID. It is a view
+// of DefaultRegistry, see ByID.
+var StateID = map[uint32]ID{}
+
+// BitsPerBlock mirrors DefaultRegistry.BitsPerBlock(), kept for callers that
+// predate Registry.
+var BitsPerBlock int
+
+// DefaultRegistry is the Registry backing the package-level ByID and StateID
+// maps.
+var DefaultRegistry Registry = &mapRegistry{byID: ByID, stateID: StateID}
+
+// NewRegistry creates an empty Registry, independent from DefaultRegistry
+// and any other Registry. Generated version packages use this to keep their
+// own block tables; see ForProtocol.
+func NewRegistry() Registry {
+	return &mapRegistry{byID: map[ID]*Block{}, stateID: map[uint32]ID{}}
+}
+
+// mapRegistry is a Registry implemented directly on top of a pair of maps,
+// guarded by a mutex so Register can be called after init from concurrent
+// goroutines.
+type mapRegistry struct {
+	mu      sync.RWMutex
+	byID    map[ID]*Block
+	stateID map[uint32]ID
+	bits    int
+}
+
+func (r *mapRegistry) Lookup(id ID) *Block {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byID[id]
+}
+
+func (r *mapRegistry) StateToID(state uint32) ID {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stateID[state]
+}
+
+func (r *mapRegistry) All() []*Block {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]*Block, 0, len(r.byID))
+	for _, b := range r.byID {
+		out = append(out, b)
+	}
+	return out
+}
+
+func (r *mapRegistry) BitsPerBlock() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.bits
+}
+
+func (r *mapRegistry) Register(b *Block) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byID[b.ID]; ok {
+		return fmt.Errorf("block: block with id %d is already registered", b.ID)
+	}
+	r.byID[b.ID] = b
+	r.addStates(b.ID, b.MinStateID, b.MaxStateID)
+	return nil
+}
+
+func (r *mapRegistry) RegisterStates(id ID, minState, maxState uint32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addStates(id, minState, maxState)
+}
+
+// addStates must be called with r.mu held.
+func (r *mapRegistry) addStates(id ID, minState, maxState uint32) {
+	for s := minState; s <= maxState; s++ {
+		r.stateID[s] = id
+	}
+	r.bits = int(math.Ceil(math.Log2(float64(len(r.stateID)))))
+}
+
+// Register adds b to the default registry. See Registry.Register.
+func Register(b *Block) error {
+	if err := DefaultRegistry.Register(b); err != nil {
+		return err
+	}
+	BitsPerBlock = DefaultRegistry.BitsPerBlock()
+	return nil
+}
+
+// RegisterStates extends the state range owned by an already-registered
+// block in the default registry. See Registry.RegisterStates.
+func RegisterStates(id ID, minState, maxState uint32) {
+	DefaultRegistry.RegisterStates(id, minState, maxState)
+	BitsPerBlock = DefaultRegistry.BitsPerBlock()
+}
+
+// must panics if err is non-nil. Generated version packages define their own
+// copy for their init code; this one has no caller in package block.
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
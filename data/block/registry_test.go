@@ -0,0 +1,35 @@
+package block
+
+import "testing"
+
+func TestRegisterDuplicateID(t *testing.T) {
+	reg := NewRegistry()
+	first := &Block{ID: 42, Name: "test_block"}
+	if err := reg.Register(first); err != nil {
+		t.Fatalf("first Register: unexpected error: %v", err)
+	}
+
+	if err := reg.Register(&Block{ID: 42, Name: "test_block_dup"}); err == nil {
+		t.Fatalf("expected an error registering a duplicate block ID, got nil")
+	}
+
+	if got := reg.Lookup(42); got != first {
+		t.Fatalf("a failed duplicate Register must not replace the existing block, got %v", got)
+	}
+}
+
+func TestRegisterExtendsStateIDAndBitsPerBlock(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(&Block{ID: 1, MinStateID: 0, MaxStateID: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	for s := uint32(0); s <= 3; s++ {
+		if got := reg.StateToID(s); got != 1 {
+			t.Errorf("StateToID(%d) = %d, want 1", s, got)
+		}
+	}
+	if bits := reg.BitsPerBlock(); bits < 2 {
+		t.Errorf("BitsPerBlock() = %d, want at least 2 to represent 4 states", bits)
+	}
+}
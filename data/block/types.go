@@ -0,0 +1,26 @@
+// Package block stores information about blocks in Minecraft.
+package block
+
+// ID describes the numeric ID of a block.
+type ID uint32
+
+// Block describes information about a type of block.
+type Block struct {
+	ID          ID
+	DisplayName string
+	Name        string
+
+	Hardness   float64
+	Diggable   bool
+	DropIDs    []uint32
+	NeedsTools map[uint32]bool
+
+	MinStateID uint32
+	MaxStateID uint32
+
+	Transparent      bool
+	FilterLightLevel int
+	EmitLightLevel   int
+
+	Properties []Property
+}
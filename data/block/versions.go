@@ -0,0 +1,27 @@
+package block
+
+import "sync"
+
+var (
+	versionsMu sync.RWMutex
+	versions   = map[int32]Registry{}
+)
+
+// RegisterVersion associates r with protocolVersion, the version number sent
+// during the login handshake, so ForProtocol can later select it. Generated
+// per-version block packages call this from their init().
+func RegisterVersion(protocolVersion int32, r Registry) {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+	versions[protocolVersion] = r
+}
+
+// ForProtocol returns the Registry generated for protocolVersion, or nil if
+// this module wasn't built with block data for that version. Callers such as
+// the chunk parser use this to pick the right table once the handshake has
+// told them which protocol version the connection speaks.
+func ForProtocol(protocolVersion int32) Registry {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+	return versions[protocolVersion]
+}
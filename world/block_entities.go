@@ -0,0 +1,141 @@
+// Package world holds client-side world state that the generated block
+// data alone has no room for: the per-instance NBT of block entities such
+// as chests, signs, skulls, beehives and command blocks.
+package world
+
+import (
+	"fmt"
+
+	"github.com/Tnze/go-mc/data/block"
+	"github.com/Tnze/go-mc/nbt"
+)
+
+// BlockPos is a block's position in world coordinates.
+type BlockPos struct {
+	X, Y, Z int
+}
+
+// ChunkPos identifies the chunk containing a BlockPos.
+type ChunkPos struct {
+	X, Z int
+}
+
+// Chunk returns the position of the chunk containing p.
+func (p BlockPos) Chunk() ChunkPos {
+	return ChunkPos{X: p.X >> 4, Z: p.Z >> 4}
+}
+
+// BlockEntities holds the per-instance NBT of block entities, keyed by their
+// world position. A chunk packet decoder populates it from a chunk's
+// BlockEntities field; UpdateBlockEntity packets mutate individual entries
+// in-place through Set.
+type BlockEntities struct {
+	byPos map[BlockPos]nbt.RawMessage
+}
+
+// NewBlockEntities creates an empty store.
+func NewBlockEntities() *BlockEntities {
+	return &BlockEntities{byPos: map[BlockPos]nbt.RawMessage{}}
+}
+
+// Set stores raw as the block entity at pos, replacing any previous value.
+func (be *BlockEntities) Set(pos BlockPos, raw nbt.RawMessage) {
+	be.byPos[pos] = raw
+}
+
+// Get returns the raw block entity NBT at pos, and whether one is stored.
+func (be *BlockEntities) Get(pos BlockPos) (nbt.RawMessage, bool) {
+	raw, ok := be.byPos[pos]
+	return raw, ok
+}
+
+// Delete removes the block entity at pos, if any.
+func (be *BlockEntities) Delete(pos BlockPos) {
+	delete(be.byPos, pos)
+}
+
+// RangeChunk calls fn for every block entity currently stored inside the
+// chunk at cp.
+func (be *BlockEntities) RangeChunk(cp ChunkPos, fn func(pos BlockPos, raw nbt.RawMessage)) {
+	for pos, raw := range be.byPos {
+		if pos.Chunk() == cp {
+			fn(pos, raw)
+		}
+	}
+}
+
+// blockEntityPos is the subset of fields every block entity NBT compound
+// carries, used to recover its world position.
+type blockEntityPos struct {
+	X int32 `nbt:"x"`
+	Y int32 `nbt:"y"`
+	Z int32 `nbt:"z"`
+}
+
+// LoadChunk replaces every block entity inside cp with those decoded from
+// raws, the BlockEntities field of a ChunkData/LevelChunk packet.
+func (be *BlockEntities) LoadChunk(cp ChunkPos, raws []nbt.RawMessage) error {
+	be.RangeChunk(cp, func(pos BlockPos, _ nbt.RawMessage) { be.Delete(pos) })
+
+	for _, raw := range raws {
+		var pos blockEntityPos
+		if err := nbt.Unmarshal(raw, &pos); err != nil {
+			return fmt.Errorf("world: decode block entity position: %w", err)
+		}
+		be.Set(BlockPos{X: int(pos.X), Y: int(pos.Y), Z: int(pos.Z)}, raw)
+	}
+	return nil
+}
+
+// SignText is the decoded NBT of a sign block entity.
+type SignText struct {
+	Text1, Text2, Text3, Text4 string
+}
+
+// ItemStack is one NBT-encoded item stored inside a container block entity.
+type ItemStack struct {
+	Slot  byte
+	ID    string         `nbt:"id"`
+	Count byte           `nbt:"Count"`
+	Tag   nbt.RawMessage `nbt:"tag,omitempty"`
+}
+
+// ChestInventory is the decoded NBT of a chest or trapped chest block
+// entity.
+type ChestInventory struct {
+	Items []ItemStack `nbt:"Items"`
+}
+
+// DecodeBlockEntity decodes raw into a well-known Go struct for the common
+// block entity kinds - currently signs and chests - based on id, looked up
+// in reg. reg must be the Registry for the connection's own protocol
+// version (e.g. from block.ForProtocol), since id is only meaningful
+// relative to the registry that produced it. Callers that need a block
+// entity kind DecodeBlockEntity doesn't recognise can still unmarshal raw
+// themselves; it is returned unchanged in that case.
+func DecodeBlockEntity(reg block.Registry, id block.ID, raw nbt.RawMessage) (any, error) {
+	b := reg.Lookup(id)
+	if b == nil {
+		return raw, nil
+	}
+
+	switch b.Name {
+	case "oak_sign", "spruce_sign", "birch_sign", "acacia_sign", "jungle_sign", "dark_oak_sign", "crimson_sign", "warped_sign",
+		"oak_wall_sign", "spruce_wall_sign", "birch_wall_sign", "acacia_wall_sign", "jungle_wall_sign", "dark_oak_wall_sign", "crimson_wall_sign", "warped_wall_sign":
+		var sign SignText
+		if err := nbt.Unmarshal(raw, &sign); err != nil {
+			return nil, fmt.Errorf("world: decode sign: %w", err)
+		}
+		return sign, nil
+
+	case "chest", "trapped_chest":
+		var chest ChestInventory
+		if err := nbt.Unmarshal(raw, &chest); err != nil {
+			return nil, fmt.Errorf("world: decode chest: %w", err)
+		}
+		return chest, nil
+
+	default:
+		return raw, nil
+	}
+}
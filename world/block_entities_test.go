@@ -0,0 +1,144 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/Tnze/go-mc/data/block"
+	"github.com/Tnze/go-mc/nbt"
+)
+
+// blockEntitySample is shaped like a real block entity: lowercase x/y/z,
+// the same keys vanilla sends, which is what LoadChunk must decode against.
+type blockEntitySample struct {
+	X  int32  `nbt:"x"`
+	Y  int32  `nbt:"y"`
+	Z  int32  `nbt:"z"`
+	ID string `nbt:"id"`
+}
+
+func TestLoadChunkRecoversBlockPos(t *testing.T) {
+	raw, err := nbt.Marshal(blockEntitySample{X: 20, Y: 65, Z: -3, ID: "minecraft:chest"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	be := NewBlockEntities()
+	cp := BlockPos{X: 20, Y: 65, Z: -3}.Chunk()
+	if err := be.LoadChunk(cp, []nbt.RawMessage{raw}); err != nil {
+		t.Fatalf("LoadChunk: %v", err)
+	}
+
+	want := BlockPos{X: 20, Y: 65, Z: -3}
+	if _, ok := be.Get(want); !ok {
+		t.Fatalf("block entity not stored at %v; nbt x/y/z tags likely aren't matching the lowercase keys vanilla sends", want)
+	}
+}
+
+func TestLoadChunkDistinguishesPositionsAcrossChunks(t *testing.T) {
+	signRaw, err := nbt.Marshal(blockEntitySample{X: 1, Y: 64, Z: 1, ID: "minecraft:sign"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	chestRaw, err := nbt.Marshal(blockEntitySample{X: 17, Y: 64, Z: 1, ID: "minecraft:chest"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	be := NewBlockEntities()
+	if err := be.LoadChunk(ChunkPos{X: 0, Z: 0}, []nbt.RawMessage{signRaw}); err != nil {
+		t.Fatalf("LoadChunk chunk 0: %v", err)
+	}
+	if err := be.LoadChunk(ChunkPos{X: 1, Z: 0}, []nbt.RawMessage{chestRaw}); err != nil {
+		t.Fatalf("LoadChunk chunk 1: %v", err)
+	}
+
+	if _, ok := be.Get(BlockPos{X: 1, Y: 64, Z: 1}); !ok {
+		t.Errorf("expected the sign to remain at its own position")
+	}
+	if _, ok := be.Get(BlockPos{X: 17, Y: 64, Z: 1}); !ok {
+		t.Errorf("expected the chest to be stored at its own position, not collapsed onto the sign's")
+	}
+}
+
+const (
+	idOakSign block.ID = 1
+	idChest   block.ID = 2
+	idStone   block.ID = 3
+)
+
+func testRegistry(t *testing.T) block.Registry {
+	t.Helper()
+	reg := block.NewRegistry()
+	for _, b := range []*block.Block{
+		{ID: idOakSign, Name: "oak_sign"},
+		{ID: idChest, Name: "chest"},
+		{ID: idStone, Name: "stone"},
+	} {
+		if err := reg.Register(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return reg
+}
+
+func TestDecodeBlockEntitySign(t *testing.T) {
+	raw, err := nbt.Marshal(SignText{Text1: "hello", Text2: "world"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	got, err := DecodeBlockEntity(testRegistry(t), idOakSign, raw)
+	if err != nil {
+		t.Fatalf("DecodeBlockEntity: %v", err)
+	}
+
+	sign, ok := got.(SignText)
+	if !ok {
+		t.Fatalf("DecodeBlockEntity returned %T, want SignText", got)
+	}
+	if sign.Text1 != "hello" || sign.Text2 != "world" {
+		t.Errorf("decoded sign = %+v, want Text1=%q Text2=%q", sign, "hello", "world")
+	}
+}
+
+func TestDecodeBlockEntityChest(t *testing.T) {
+	raw, err := nbt.Marshal(ChestInventory{
+		Items: []ItemStack{{Slot: 0, ID: "minecraft:diamond", Count: 3}},
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	got, err := DecodeBlockEntity(testRegistry(t), idChest, raw)
+	if err != nil {
+		t.Fatalf("DecodeBlockEntity: %v", err)
+	}
+
+	chest, ok := got.(ChestInventory)
+	if !ok {
+		t.Fatalf("DecodeBlockEntity returned %T, want ChestInventory", got)
+	}
+	if len(chest.Items) != 1 || chest.Items[0].ID != "minecraft:diamond" || chest.Items[0].Count != 3 {
+		t.Errorf("decoded chest = %+v, want one diamond stack of 3", chest)
+	}
+}
+
+func TestDecodeBlockEntityUnknownIDReturnsRawUnchanged(t *testing.T) {
+	raw, err := nbt.Marshal(blockEntitySample{X: 1, Y: 2, Z: 3, ID: "minecraft:stone"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	got, err := DecodeBlockEntity(testRegistry(t), idStone, raw)
+	if err != nil {
+		t.Fatalf("DecodeBlockEntity: %v", err)
+	}
+
+	gotRaw, ok := got.(nbt.RawMessage)
+	if !ok {
+		t.Fatalf("DecodeBlockEntity returned %T, want nbt.RawMessage for an unrecognized block entity kind", got)
+	}
+	if string(gotRaw) != string(raw) {
+		t.Errorf("DecodeBlockEntity changed the raw NBT for an unrecognized kind")
+	}
+}